@@ -5,43 +5,49 @@ import (
 	"fmt"
 	jwt "github.com/golang-jwt/jwt/v4"
 	"github.com/gorilla/mux"
+	"golang.org/x/crypto/bcrypt"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type APIServer struct {
 	listenAddr string
 	store      Storage
+	tokenStore TokenStore
 }
 
-func NewApiServer(listenAddr string, store Storage) *APIServer {
-	return &APIServer{listenAddr: listenAddr, store: store}
+func NewApiServer(listenAddr string, store Storage, tokenStore TokenStore) *APIServer {
+	return &APIServer{listenAddr: listenAddr, store: store, tokenStore: tokenStore}
 }
 
-// Run New router for account endpoint
-func (s *APIServer) Run() {
+// newRouter builds the mux.Router with every route and its withRole
+// middleware wired up. Pulled out of Run so tests can drive requests
+// through the real middleware stack instead of calling handlers directly.
+func (s *APIServer) newRouter() *mux.Router {
 	router := mux.NewRouter()
 
-	router.HandleFunc("/account", makeHttpHandleFunc(s.handleRequest))
-	router.HandleFunc("/account/{id}", withJWTAuth(makeHttpHandleFunc(s.handleRequestWithParameter), s.store))
-	router.HandleFunc("/transfer", makeHttpHandleFunc(s.handleTransfer))
+	router.HandleFunc("/login", makeHttpHandleFunc(s.handleLogin)).Methods("POST")
+	router.HandleFunc("/auth/refresh", makeHttpHandleFunc(s.handleRefresh)).Methods("POST")
+	router.HandleFunc("/auth/logout", withRole("")(makeHttpHandleFunc(s.handleLogout), s.store, s.tokenStore)).Methods("POST")
+	router.HandleFunc("/account", withRole("admin")(makeHttpHandleFunc(s.handleGetAccounts), s.store, s.tokenStore)).Methods("GET")
+	router.HandleFunc("/account", withRole("")(makeHttpHandleFunc(s.handleCreateAccount), s.store, s.tokenStore)).Methods("POST")
+	router.HandleFunc("/account/{id}", withRole("admin")(makeHttpHandleFunc(s.handleRequestWithParameter), s.store, s.tokenStore))
+	router.HandleFunc("/transfer", withRole("")(makeHttpHandleFunc(s.handleTransfer), s.store, s.tokenStore)).Methods("POST")
 
-	log.Println("Json API Service running on port: ", s.listenAddr)
-
-	http.ListenAndServe(s.listenAddr, router)
+	return router
 }
 
-func (s *APIServer) handleRequest(w http.ResponseWriter, r *http.Request) error {
-	if r.Method == "GET" {
-		return s.handleGetAccounts(w, r)
-	}
-	if r.Method == "POST" {
-		return s.handleCreateAccount(w, r)
-	}
+// Run New router for account endpoint
+func (s *APIServer) Run() {
+	router := s.newRouter()
 
-	return fmt.Errorf("method not allowed %s", r.Method)
+	log.Println("Json API Service running on port: ", s.listenAddr)
+
+	http.ListenAndServe(s.listenAddr, router)
 }
 
 func (s *APIServer) handleRequestWithParameter(w http.ResponseWriter, r *http.Request) error {
@@ -52,52 +58,96 @@ func (s *APIServer) handleRequestWithParameter(w http.ResponseWriter, r *http.Re
 		return s.handleDeleteAccount(w, r)
 	}
 
-	return fmt.Errorf("method not allowed %s", r.Method)
+	return NewAPIError(http.StatusMethodNotAllowed, "method_not_allowed", fmt.Sprintf("method not allowed %s", r.Method))
 }
 
 func (s *APIServer) handleGetAccountById(w http.ResponseWriter, r *http.Request) error {
 	idStr := mux.Vars(r)["id"]
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		return fmt.Errorf("invalid id given: %s", idStr)
+		return NewAPIError(http.StatusBadRequest, "invalid_argument", fmt.Sprintf("invalid id given: %s", idStr))
 	}
 	account, err := s.store.GetAccountById(id)
 	if err != nil {
-		// No records found: Return status 200 without empty json according to RFC
-		return WriteJson(w, http.StatusOK, err)
+		return err
 	}
 
 	return WriteJson(w, http.StatusOK, account)
 }
 
 func (s *APIServer) handleGetAccounts(w http.ResponseWriter, r *http.Request) error {
+	params, err := listAccountsParamsFromRequest(r)
+	if err != nil {
+		return err
+	}
 
-	accounts, err := s.store.GetAccounts()
+	accounts, total, err := s.store.GetAccounts(*params)
 	if err != nil {
 		return err
 	}
 
-	return WriteJson(w, http.StatusOK, accounts)
+	result := ListAccountsResult{Data: accounts, Total: total}
+	if int64(params.PageID)*int64(params.PageSize) < total {
+		next := params.PageID + 1
+		result.NextPageID = &next
+	}
+
+	return WriteJson(w, http.StatusOK, result)
+}
+
+// listAccountsParamsFromRequest parses page_id/page_size query params on
+// GET /account, defaulting to page 1 of 10 and capping page_size at 100 so
+// a client can't force an unbounded table scan.
+func listAccountsParamsFromRequest(r *http.Request) (*ListAccountsParams, error) {
+	params := ListAccountsParams{PageID: 1, PageSize: 10}
+
+	if v := r.URL.Query().Get("page_id"); v != "" {
+		pageID, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, NewAPIError(http.StatusBadRequest, "invalid_argument", fmt.Sprintf("invalid page_id: %s", v))
+		}
+		params.PageID = int32(pageID)
+	}
+	if v := r.URL.Query().Get("page_size"); v != "" {
+		pageSize, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, NewAPIError(http.StatusBadRequest, "invalid_argument", fmt.Sprintf("invalid page_size: %s", v))
+		}
+		params.PageSize = int32(pageSize)
+	}
+	if params.PageSize > 100 {
+		params.PageSize = 100
+	}
+
+	if err := validate.Struct(params); err != nil {
+		return nil, validationErrorFrom(err)
+	}
+
+	return &params, nil
 }
 
 func (s *APIServer) handleCreateAccount(w http.ResponseWriter, r *http.Request) error {
 	createAccountReq := new(CreateAccountRequest)
-	// createAccountReq := CreateAccountRequest{}
-	if err := json.NewDecoder(r.Body).Decode( /*&*/ createAccountReq); err != nil {
+	if err := decodeJSON(r, createAccountReq); err != nil {
 		return err
 	}
-
-	account := NewAccount(createAccountReq.FirstName, createAccountReq.LastName)
-	if err := s.store.CreateAccount(account); err != nil {
-		return err
+	if err := validate.Struct(createAccountReq); err != nil {
+		return validationErrorFrom(err)
 	}
 
-	tokenString, err := createJWT(account)
+	owner, err := userFromRequest(r, s.store, s.tokenStore)
 	if err != nil {
-		return err
+		return ErrUnauthorized
 	}
 
-	fmt.Println("JWT token: ", tokenString)
+	currency := createAccountReq.Currency
+	if currency == "" {
+		currency = "USD"
+	}
+	account := NewAccount(createAccountReq.FirstName, createAccountReq.LastName, currency, owner.ID)
+	if err := s.store.CreateAccount(account); err != nil {
+		return err
+	}
 
 	return WriteJson(w, http.StatusCreated, createAccountReq)
 }
@@ -106,7 +156,7 @@ func (s *APIServer) handleDeleteAccount(w http.ResponseWriter, r *http.Request)
 	idStr := mux.Vars(r)["id"]
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		return fmt.Errorf("invalid id given: %s", idStr)
+		return NewAPIError(http.StatusBadRequest, "invalid_argument", fmt.Sprintf("invalid id given: %s", idStr))
 	}
 	err = s.store.DeleteAccount(id)
 	if err != nil {
@@ -115,88 +165,322 @@ func (s *APIServer) handleDeleteAccount(w http.ResponseWriter, r *http.Request)
 	return WriteJson(w, http.StatusOK, map[string]int{"deleted": id})
 }
 
+func (s *APIServer) handleLogin(w http.ResponseWriter, r *http.Request) error {
+	loginReq := new(LoginRequest)
+	if err := decodeJSON(r, loginReq); err != nil {
+		return err
+	}
+
+	user, err := s.store.GetUserByUsername(loginReq.Username)
+	if err != nil {
+		return ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(loginReq.Password)); err != nil {
+		return ErrInvalidCredentials
+	}
+
+	access, refresh, err := issueTokenPair(s.tokenStore, user, newFamily())
+	if err != nil {
+		return err
+	}
+
+	return WriteJson(w, http.StatusOK, LoginResponse{AccessToken: access, RefreshToken: refresh, User: user})
+}
+
+func (s *APIServer) handleRefresh(w http.ResponseWriter, r *http.Request) error {
+	req := new(RefreshRequest)
+	if err := decodeJSON(r, req); err != nil {
+		return err
+	}
+
+	stored, err := s.tokenStore.GetRefreshToken(req.RefreshToken)
+	if err != nil {
+		return ErrUnauthorized
+	}
+
+	if stored.Revoked || stored.Used || time.Now().UTC().After(stored.ExpiresAt) {
+		// A reused or expired token is a sign of theft: burn the whole family.
+		_ = s.tokenStore.RevokeFamily(stored.Family)
+		return ErrUnauthorized
+	}
+
+	user, err := s.store.GetUserById(stored.UserID)
+	if err != nil {
+		return ErrUnauthorized
+	}
+
+	if err := s.tokenStore.MarkRefreshTokenUsed(stored.ID); err != nil {
+		return err
+	}
+
+	access, refresh, err := issueTokenPair(s.tokenStore, user, stored.Family)
+	if err != nil {
+		return err
+	}
+
+	return WriteJson(w, http.StatusOK, LoginResponse{AccessToken: access, RefreshToken: refresh, User: user})
+}
+
+func (s *APIServer) handleLogout(w http.ResponseWriter, r *http.Request) error {
+	claims, err := claimsFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	family, _ := claims["fam"].(string)
+	if family == "" {
+		return ErrUnauthorized
+	}
+
+	if err := s.tokenStore.RevokeFamily(family); err != nil {
+		return err
+	}
+
+	// Revoking the family stops future refreshes and, because
+	// userFromRequest checks the family, rejects every access token issued
+	// in it. Denylist the current token's jti as well, so that token is
+	// also rejected by the jti check.
+	if jti, _ := claims["jti"].(string); jti != "" {
+		if err := s.tokenStore.RevokeJTI(jti); err != nil {
+			return err
+		}
+	}
+
+	return WriteJson(w, http.StatusOK, map[string]string{"status": "logged out"})
+}
+
 func (s *APIServer) handleTransfer(w http.ResponseWriter, r *http.Request) error {
 	transferReq := new(TransferRequest)
-	if err := json.NewDecoder(r.Body).Decode(transferReq); err != nil {
+	if err := decodeJSON(r, transferReq); err != nil {
 		return err
 	}
 	defer r.Body.Close()
+	if err := validate.Struct(transferReq); err != nil {
+		return validationErrorFrom(err)
+	}
+
+	user, err := userFromRequest(r, s.store, s.tokenStore)
+	if err != nil {
+		return ErrUnauthorized
+	}
+
+	fromAccount, err := s.store.GetAccountById(transferReq.FromAccount)
+	if err != nil {
+		return err
+	}
+	if !user.HasRole("admin") && fromAccount.UserID != user.ID {
+		return ErrForbidden
+	}
+
+	result, err := s.store.TransferTx(r.Context(), TransferTxParams{
+		FromAccountID: transferReq.FromAccount,
+		ToAccountID:   transferReq.ToAccount,
+		Amount:        transferReq.Amount,
+	})
+	if err != nil {
+		return err
+	}
 
-	return WriteJson(w, http.StatusOK, transferReq)
+	return WriteJson(w, http.StatusOK, result)
 }
 
-func permissionDenied(w http.ResponseWriter) {
-	WriteJson(w, http.StatusForbidden, ApiError{Error: "permission denied"})
+func permissionDenied(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, r, apiErrorFromErr(ErrForbidden))
 }
 
-func withJWTAuth(handlerFunc http.HandlerFunc, s Storage) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		tokenString := r.Header.Get("Authorization")
-		if strings.HasPrefix(tokenString, "Bearer ") {
-			tokenString = strings.TrimPrefix(tokenString, "Bearer ")
-		}
-		token, err := validateJWT(tokenString)
-		if err != nil {
-			permissionDenied(w)
-			return
-		}
-		if !token.Valid {
-			permissionDenied(w)
-			return
-		}
+// claimsFromRequest validates the bearer token on r and returns its claims.
+func claimsFromRequest(r *http.Request) (jwt.MapClaims, error) {
+	tokenString := r.Header.Get("Authorization")
+	if strings.HasPrefix(tokenString, "Bearer ") {
+		tokenString = strings.TrimPrefix(tokenString, "Bearer ")
+	}
+	token, err := validateJWT(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, ErrUnauthorized
+	}
+
+	return token.Claims.(jwt.MapClaims), nil
+}
+
+// userFromRequest validates the bearer token on r, checks that its token
+// family and its own jti haven't been revoked, and loads the User it
+// identifies (via the "sub" claim) from the store.
+func userFromRequest(r *http.Request, s Storage, ts TokenStore) (*User, error) {
+	claims, err := claimsFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
 
-		// Get user id from path variable
-		idStr := mux.Vars(r)["id"]
-		id, err := strconv.Atoi(idStr)
+	family, _ := claims["fam"].(string)
+	if family == "" {
+		return nil, ErrUnauthorized
+	}
+	revoked, err := ts.IsFamilyRevoked(family)
+	if err != nil || revoked {
+		return nil, ErrUnauthorized
+	}
 
-		account, err := s.GetAccountById(id)
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return nil, ErrUnauthorized
+	}
+	jtiRevoked, err := ts.IsJTIRevoked(jti)
+	if err != nil || jtiRevoked {
+		return nil, ErrUnauthorized
+	}
 
-		if err != nil {
-			permissionDenied(w)
-			return
-		}
+	// turns out numeric claims come back as float64 !?!
+	userId, err := strconv.Atoi(fmt.Sprintf("%v", claims["sub"]))
+	if err != nil {
+		return nil, err
+	}
 
-		// Extract claims from JWT token
-		claims := token.Claims.(jwt.MapClaims)
-		// panic(reflect.TypeOf(claims["accountNumber"]))
-		// turns out that claim accountNumber is of type float64 !?!
-		if account.Number != int64(claims["accountNumber"].(float64)) {
-			permissionDenied(w)
-			return
+	return s.GetUserById(userId)
+}
+
+// withRole is a middleware factory: it parses the caller's JWT, loads the
+// corresponding User from the store, and only invokes handlerFunc if the
+// user has the given role. Pass role "" to mean "any authenticated user" —
+// every such caller passes straight through. A non-admin user who lacks
+// role is still let through for GET requests against a resource they own
+// (e.g. their own account).
+func withRole(role string) func(http.HandlerFunc, Storage, TokenStore) http.HandlerFunc {
+	return func(handlerFunc http.HandlerFunc, s Storage, ts TokenStore) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			user, err := userFromRequest(r, s, ts)
+			if err != nil {
+				writeProblem(w, r, apiErrorFromErr(err))
+				return
+			}
+
+			if role == "" || user.HasRole("admin") || user.HasRole(role) {
+				handlerFunc(w, r)
+				return
+			}
+
+			// Not privileged for this route: fall back to letting the
+			// account's owner read (but never delete) their own account.
+			idStr := mux.Vars(r)["id"]
+			if idStr == "" || r.Method != "GET" {
+				permissionDenied(w, r)
+				return
+			}
+			id, err := strconv.Atoi(idStr)
+			if err != nil {
+				permissionDenied(w, r)
+				return
+			}
+			account, err := s.GetAccountById(id)
+			if err != nil || account.UserID != user.ID {
+				permissionDenied(w, r)
+				return
+			}
+
+			handlerFunc(w, r)
 		}
+	}
+}
+
+// jwtSecret is read once at startup by loadJWTSecret; see main.go.
+var jwtSecret []byte
+
+func loadJWTSecret() error {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return fmt.Errorf("JWT_SECRET environment variable must be set")
+	}
+	jwtSecret = []byte(secret)
+
+	return nil
+}
 
-		handlerFunc(w, r)
+func newFamily() string {
+	family, err := newTokenID()
+	if err != nil {
+		// Extremely unlikely: crypto/rand failure. Fall back to a
+		// per-process-unique value rather than leaving it empty.
+		return fmt.Sprintf("fam-%d", time.Now().UnixNano())
 	}
+	return family
 }
 
-// For demo purpose only
-const secret = "SuperSafePass123"
+// createAccessToken issues a short-lived JWT carrying the user's roles,
+// the token family it belongs to, and its own jti, so userFromRequest can
+// reject it if the family is revoked (logout) or this specific jti is
+// individually denylisted (logout also revokes the jti of the token that
+// requested it).
+func createAccessToken(user *User, family string) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
 
-func createJWT(account *Account) (string, error) {
-	issuer := account.LastName + " " + account.FirstName
+	now := time.Now().UTC()
 	claims := &jwt.MapClaims{
-		// "expiresAt":     15000,
-		"accountNumber": account.Number,
-		"issuer":        issuer,
+		"sub":   user.ID,
+		"roles": user.Roles,
+		"fam":   family,
+		"jti":   jti,
+		"iat":   now.Unix(),
+		"exp":   now.Add(accessTokenTTL).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	return token.SignedString([]byte(secret))
+	return token.SignedString(jwtSecret)
+}
+
+// issueTokenPair mints a fresh access token plus a new refresh token row in
+// the given family, persisting the refresh token via ts.
+func issueTokenPair(ts TokenStore, user *User, family string) (access string, refresh string, err error) {
+	access, err = createAccessToken(user, family)
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshID, err := newTokenID()
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now().UTC()
+	rt := &RefreshToken{
+		ID:        refreshID,
+		Family:    family,
+		UserID:    user.ID,
+		ExpiresAt: now.Add(refreshTokenTTL),
+		CreatedAt: now,
+	}
+	if err := ts.CreateRefreshToken(rt); err != nil {
+		return "", "", err
+	}
+
+	return access, refreshID, nil
 }
 
 func validateJWT(tokenString string) (*jwt.Token, error) {
-	// TODO: Warning, use env to store secrets instead of const secret
-	// secret := os.Getenv("JWT_SECRET");
 	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		// Don't forget to validate the alg is what you expect:
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		// hmacSampleSecret is a []byte containing your secret, e.g. []byte("my_secret_key")
-		return []byte(secret), nil
+		return jwtSecret, nil
 	})
 }
 
+// decodeJSON decodes r's body into v, wrapping a malformed body as a 400
+// APIError instead of letting the raw decode error fall through to
+// apiErrorFromErr's 500 default.
+func decodeJSON(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return NewAPIError(http.StatusBadRequest, "invalid_argument", fmt.Sprintf("invalid request body: %s", err))
+	}
+	return nil
+}
+
 func WriteJson(w http.ResponseWriter, status int, v any) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -206,15 +490,13 @@ func WriteJson(w http.ResponseWriter, status int, v any) error {
 
 type apiFunc func(w http.ResponseWriter, r *http.Request) error
 
-type ApiError struct {
-	Error string `json:"error"`
-}
-
+// makeHttpHandleFunc adapts an apiFunc into an http.HandlerFunc, rendering
+// any returned error as an RFC 7807 problem+json body with the right status
+// code (see apiErrorFromErr).
 func makeHttpHandleFunc(f apiFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if err := f(w, r); err != nil {
-			// handle the error here
-			WriteJson(w, http.StatusBadRequest, ApiError{Error: err.Error()})
+			writeProblem(w, r, apiErrorFromErr(err))
 		}
 	}
 }
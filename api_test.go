@@ -0,0 +1,458 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func init() {
+	jwtSecret = []byte("test-secret")
+}
+
+// fakeTokenStore is a hand-rolled, in-memory TokenStore. The handlers under
+// test only ever check or revoke a token family or a single jti, so two maps
+// are enough and we don't need a generated mock for it on top of MockStorage.
+type fakeTokenStore struct {
+	revoked     map[string]bool
+	revokedJTIs map[string]bool
+}
+
+func newFakeTokenStore() *fakeTokenStore {
+	return &fakeTokenStore{revoked: make(map[string]bool), revokedJTIs: make(map[string]bool)}
+}
+
+func (f *fakeTokenStore) CreateRefreshToken(*RefreshToken) error { return nil }
+
+func (f *fakeTokenStore) GetRefreshToken(id string) (*RefreshToken, error) {
+	return nil, ErrUnauthorized
+}
+
+func (f *fakeTokenStore) MarkRefreshTokenUsed(id string) error { return nil }
+
+func (f *fakeTokenStore) RevokeFamily(family string) error {
+	f.revoked[family] = true
+	return nil
+}
+
+func (f *fakeTokenStore) IsFamilyRevoked(family string) (bool, error) {
+	return f.revoked[family], nil
+}
+
+func (f *fakeTokenStore) RevokeJTI(jti string) error {
+	f.revokedJTIs[jti] = true
+	return nil
+}
+
+func (f *fakeTokenStore) IsJTIRevoked(jti string) (bool, error) {
+	return f.revokedJTIs[jti], nil
+}
+
+// authHeader mints a real access token for user via createAccessToken, so
+// tests exercise the same JWT parsing path the handlers use in production.
+func authHeader(t *testing.T, user *User, family string) string {
+	t.Helper()
+
+	token, err := createAccessToken(user, family)
+	if err != nil {
+		t.Fatalf("createAccessToken: %v", err)
+	}
+
+	return "Bearer " + token
+}
+
+func jsonBody(t *testing.T, v any) *bytes.Buffer {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		t.Fatalf("encode body: %v", err)
+	}
+
+	return buf
+}
+
+func TestHandleGetAccountById(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	server := NewApiServer(":0", store, newFakeTokenStore())
+
+	account := &Account{ID: 1, FirstName: "Ada", LastName: "Lovelace", Currency: "USD"}
+	store.EXPECT().GetAccountById(1).Return(account, nil)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/account/1", nil), map[string]string{"id": "1"})
+	w := httptest.NewRecorder()
+
+	if err := server.handleGetAccountById(w, req); err != nil {
+		t.Fatalf("handleGetAccountById: %v", err)
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestHandleGetAccountByIdNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	server := NewApiServer(":0", store, newFakeTokenStore())
+
+	store.EXPECT().GetAccountById(99).Return(nil, ErrAccountNotFound)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/account/99", nil), map[string]string{"id": "99"})
+	w := httptest.NewRecorder()
+
+	err := server.handleGetAccountById(w, req)
+	if !errors.Is(err, ErrAccountNotFound) {
+		t.Fatalf("expected ErrAccountNotFound, got %v", err)
+	}
+}
+
+func TestHandleGetAccountsPaginates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	server := NewApiServer(":0", store, newFakeTokenStore())
+
+	store.EXPECT().
+		GetAccounts(ListAccountsParams{PageID: 1, PageSize: 10}).
+		Return([]*Account{{ID: 1}}, int64(15), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/account", nil)
+	w := httptest.NewRecorder()
+
+	if err := server.handleGetAccounts(w, req); err != nil {
+		t.Fatalf("handleGetAccounts: %v", err)
+	}
+
+	var result ListAccountsResult
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if result.Total != 15 {
+		t.Errorf("expected total 15, got %d", result.Total)
+	}
+	if result.NextPageID == nil || *result.NextPageID != 2 {
+		t.Errorf("expected next_page_id 2, got %v", result.NextPageID)
+	}
+}
+
+func TestHandleGetAccountsStoreError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	server := NewApiServer(":0", store, newFakeTokenStore())
+
+	store.EXPECT().GetAccounts(gomock.Any()).Return(nil, int64(0), errors.New("connection refused"))
+
+	req := httptest.NewRequest(http.MethodGet, "/account", nil)
+	w := httptest.NewRecorder()
+
+	if err := server.handleGetAccounts(w, req); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}
+
+func TestHandleCreateAccountMalformedBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	server := NewApiServer(":0", store, newFakeTokenStore())
+
+	req := httptest.NewRequest(http.MethodPost, "/account", bytes.NewBufferString("{not json"))
+	w := httptest.NewRecorder()
+
+	err := server.handleCreateAccount(w, req)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusBadRequest {
+		t.Fatalf("expected a 400 APIError, got %v", err)
+	}
+}
+
+func TestHandleCreateAccountValidationFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	server := NewApiServer(":0", store, newFakeTokenStore())
+
+	body := jsonBody(t, CreateAccountRequest{Currency: "USD"})
+	req := httptest.NewRequest(http.MethodPost, "/account", body)
+	w := httptest.NewRecorder()
+
+	err := server.handleCreateAccount(w, req)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected a 422 validation APIError, got %v", err)
+	}
+}
+
+func TestHandleCreateAccountAuthMissing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	server := NewApiServer(":0", store, newFakeTokenStore())
+
+	body := jsonBody(t, CreateAccountRequest{FirstName: "Ada", LastName: "Lovelace", Currency: "USD"})
+	req := httptest.NewRequest(http.MethodPost, "/account", body)
+	w := httptest.NewRecorder()
+
+	err := server.handleCreateAccount(w, req)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestHandleCreateAccountSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	server := NewApiServer(":0", store, newFakeTokenStore())
+
+	owner := &User{ID: 1, Username: "ada", Roles: []string{}}
+	store.EXPECT().GetUserById(1).Return(owner, nil)
+	store.EXPECT().CreateAccount(gomock.Any()).DoAndReturn(func(acc *Account) error {
+		acc.ID = 42
+		return nil
+	})
+
+	body := jsonBody(t, CreateAccountRequest{FirstName: "Ada", LastName: "Lovelace", Currency: "USD"})
+	req := httptest.NewRequest(http.MethodPost, "/account", body)
+	req.Header.Set("Authorization", authHeader(t, owner, "fam-1"))
+	w := httptest.NewRecorder()
+
+	if err := server.handleCreateAccount(w, req); err != nil {
+		t.Fatalf("handleCreateAccount: %v", err)
+	}
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+}
+
+func TestHandleTransferWrongUserForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	server := NewApiServer(":0", store, newFakeTokenStore())
+
+	caller := &User{ID: 1, Roles: []string{}}
+	fromAccount := &Account{ID: 10, UserID: 2} // owned by someone else
+
+	store.EXPECT().GetUserById(1).Return(caller, nil)
+	store.EXPECT().GetAccountById(10).Return(fromAccount, nil)
+
+	body := jsonBody(t, TransferRequest{FromAccount: 10, ToAccount: 20, Amount: 50})
+	req := httptest.NewRequest(http.MethodPost, "/transfer", body)
+	req.Header.Set("Authorization", authHeader(t, caller, "fam-1"))
+	w := httptest.NewRecorder()
+
+	err := server.handleTransfer(w, req)
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestHandleTransferAuthMissing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	server := NewApiServer(":0", store, newFakeTokenStore())
+
+	body := jsonBody(t, TransferRequest{FromAccount: 10, ToAccount: 20, Amount: 50})
+	req := httptest.NewRequest(http.MethodPost, "/transfer", body)
+	w := httptest.NewRecorder()
+
+	err := server.handleTransfer(w, req)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestHandleTransferStoreError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	server := NewApiServer(":0", store, newFakeTokenStore())
+
+	caller := &User{ID: 1, Roles: []string{}}
+	fromAccount := &Account{ID: 10, UserID: 1}
+
+	store.EXPECT().GetUserById(1).Return(caller, nil)
+	store.EXPECT().GetAccountById(10).Return(fromAccount, nil)
+	store.EXPECT().TransferTx(gomock.Any(), TransferTxParams{FromAccountID: 10, ToAccountID: 20, Amount: 50}).
+		Return(TransferTxResult{}, ErrInsufficientBalance)
+
+	body := jsonBody(t, TransferRequest{FromAccount: 10, ToAccount: 20, Amount: 50})
+	req := httptest.NewRequest(http.MethodPost, "/transfer", body)
+	req.Header.Set("Authorization", authHeader(t, caller, "fam-1"))
+	w := httptest.NewRecorder()
+
+	err := server.handleTransfer(w, req)
+	if !errors.Is(err, ErrInsufficientBalance) {
+		t.Fatalf("expected ErrInsufficientBalance, got %v", err)
+	}
+}
+
+func TestHandleTransferSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	server := NewApiServer(":0", store, newFakeTokenStore())
+
+	caller := &User{ID: 1, Roles: []string{}}
+	fromAccount := &Account{ID: 10, UserID: 1}
+
+	store.EXPECT().GetUserById(1).Return(caller, nil)
+	store.EXPECT().GetAccountById(10).Return(fromAccount, nil)
+	store.EXPECT().TransferTx(gomock.Any(), TransferTxParams{FromAccountID: 10, ToAccountID: 20, Amount: 50}).
+		Return(TransferTxResult{Transfer: &Transfer{ID: 1}}, nil)
+
+	body := jsonBody(t, TransferRequest{FromAccount: 10, ToAccount: 20, Amount: 50})
+	req := httptest.NewRequest(http.MethodPost, "/transfer", body)
+	req.Header.Set("Authorization", authHeader(t, caller, "fam-1"))
+	w := httptest.NewRecorder()
+
+	if err := server.handleTransfer(w, req); err != nil {
+		t.Fatalf("handleTransfer: %v", err)
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestHandleLoginSuccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	server := NewApiServer(":0", store, newFakeTokenStore())
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("s3cret"), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword: %v", err)
+	}
+	user := &User{ID: 1, Username: "ada", Password: string(hash), Roles: []string{}}
+	store.EXPECT().GetUserByUsername("ada").Return(user, nil)
+
+	body := jsonBody(t, LoginRequest{Username: "ada", Password: "s3cret"})
+	req := httptest.NewRequest(http.MethodPost, "/login", body)
+	w := httptest.NewRecorder()
+
+	if err := server.handleLogin(w, req); err != nil {
+		t.Fatalf("handleLogin: %v", err)
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+// TestRouterAllowsNonAdminOnOpenRoutes drives requests through the real
+// mux + withRole middleware (not the handler directly) for routes that
+// withRole("") is meant to open to any authenticated caller, regardless
+// of role. A non-admin caller must never see 401/403 from these.
+func TestRouterAllowsNonAdminOnOpenRoutes(t *testing.T) {
+	caller := &User{ID: 1, Username: "ada", Roles: []string{}}
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   any
+		expect func(store *MockStorage)
+	}{
+		{
+			name:   "create account",
+			method: http.MethodPost,
+			path:   "/account",
+			body:   CreateAccountRequest{FirstName: "Ada", LastName: "Lovelace", Currency: "USD"},
+			expect: func(store *MockStorage) {
+				store.EXPECT().GetUserById(1).Return(caller, nil).AnyTimes()
+				store.EXPECT().CreateAccount(gomock.Any()).DoAndReturn(func(acc *Account) error {
+					acc.ID = 42
+					return nil
+				})
+			},
+		},
+		{
+			name:   "transfer",
+			method: http.MethodPost,
+			path:   "/transfer",
+			body:   TransferRequest{FromAccount: 10, ToAccount: 20, Amount: 50},
+			expect: func(store *MockStorage) {
+				store.EXPECT().GetUserById(1).Return(caller, nil).AnyTimes()
+				store.EXPECT().GetAccountById(10).Return(&Account{ID: 10, UserID: 1}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), TransferTxParams{FromAccountID: 10, ToAccountID: 20, Amount: 50}).
+					Return(TransferTxResult{Transfer: &Transfer{ID: 1}}, nil)
+			},
+		},
+		{
+			name:   "logout",
+			method: http.MethodPost,
+			path:   "/auth/logout",
+			expect: func(store *MockStorage) {
+				store.EXPECT().GetUserById(1).Return(caller, nil).AnyTimes()
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := NewMockStorage(ctrl)
+			tokenStore := newFakeTokenStore()
+			server := NewApiServer(":0", store, tokenStore)
+			tc.expect(store)
+
+			var body *bytes.Buffer
+			if tc.body != nil {
+				body = jsonBody(t, tc.body)
+			} else {
+				body = jsonBody(t, struct{}{})
+			}
+			req := httptest.NewRequest(tc.method, tc.path, body)
+			req.Header.Set("Authorization", authHeader(t, caller, "fam-1"))
+			w := httptest.NewRecorder()
+
+			server.newRouter().ServeHTTP(w, req)
+
+			if w.Code == http.StatusUnauthorized || w.Code == http.StatusForbidden {
+				t.Fatalf("non-admin caller rejected by RBAC middleware: status %d, body %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleLogoutRevokesCurrentAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	tokenStore := newFakeTokenStore()
+	server := NewApiServer(":0", store, tokenStore)
+
+	caller := &User{ID: 1, Roles: []string{}}
+	token := authHeader(t, caller, "fam-1")
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	req.Header.Set("Authorization", token)
+	w := httptest.NewRecorder()
+
+	if err := server.handleLogout(w, req); err != nil {
+		t.Fatalf("handleLogout: %v", err)
+	}
+
+	authedReq := httptest.NewRequest(http.MethodGet, "/account/1", nil)
+	authedReq.Header.Set("Authorization", token)
+
+	if _, err := userFromRequest(authedReq, store, tokenStore); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected the logged-out access token to be rejected, got %v", err)
+	}
+}
+
+func TestHandleLoginInvalidCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	server := NewApiServer(":0", store, newFakeTokenStore())
+
+	store.EXPECT().GetUserByUsername("ghost").Return(nil, ErrUserNotFound)
+
+	body := jsonBody(t, LoginRequest{Username: "ghost", Password: "whatever"})
+	req := httptest.NewRequest(http.MethodPost, "/login", body)
+	w := httptest.NewRecorder()
+
+	err := server.handleLogin(w, req)
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+	}
+}
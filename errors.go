@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validate is shared across handlers: it's safe for concurrent use and
+// caches struct field metadata, so it's built once rather than per request.
+// Its tag name func reports each field by its "json" tag, so validation
+// errors key off the same names a client sent in the request body.
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" || name == "" {
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
+
+// Sentinel errors handlers and the store return so makeHttpHandleFunc can
+// translate them into the right HTTP status without string-matching.
+var (
+	ErrAccountNotFound    = errors.New("account not found")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+)
+
+// APIError is what handlers return for any failure that needs a specific
+// status code, machine-readable code, or extra detail (e.g. validation
+// failures listing the offending fields). Handlers that don't care about
+// the exact status can keep returning a plain error or a sentinel above;
+// makeHttpHandleFunc maps those to a sensible default.
+type APIError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details any    `json:"details,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+func NewAPIError(status int, code, message string) *APIError {
+	return &APIError{Status: status, Code: code, Message: message}
+}
+
+func NewValidationError(details any) *APIError {
+	return &APIError{
+		Status:  http.StatusUnprocessableEntity,
+		Code:    "validation_error",
+		Message: "request failed validation",
+		Details: details,
+	}
+}
+
+// validationErrorFrom turns a validator.ValidationErrors into an APIError
+// whose Details list which fields failed and why, keyed by JSON field name.
+func validationErrorFrom(err error) *APIError {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return NewValidationError(err.Error())
+	}
+
+	details := make(map[string]string, len(verrs))
+	for _, fe := range verrs {
+		details[fe.Field()] = fmt.Sprintf("failed on the %q rule", fe.Tag())
+	}
+
+	return NewValidationError(details)
+}
+
+// apiErrorFromErr maps any error returned by a handler to an *APIError,
+// defaulting to 500 for anything it doesn't recognize so internal details
+// never leak to the client.
+func apiErrorFromErr(err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	switch {
+	case errors.Is(err, ErrAccountNotFound), errors.Is(err, ErrUserNotFound):
+		return NewAPIError(http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, ErrUnauthorized), errors.Is(err, ErrInvalidCredentials):
+		return NewAPIError(http.StatusUnauthorized, "unauthorized", err.Error())
+	case errors.Is(err, ErrForbidden):
+		return NewAPIError(http.StatusForbidden, "forbidden", err.Error())
+	case errors.Is(err, ErrInsufficientBalance), errors.Is(err, ErrCurrencyMismatch):
+		return NewAPIError(http.StatusUnprocessableEntity, "validation_error", err.Error())
+	default:
+		return NewAPIError(http.StatusInternalServerError, "internal_error", "an unexpected error occurred")
+	}
+}
+
+// writeProblem renders apiErr as an RFC 7807 application/problem+json body.
+func writeProblem(w http.ResponseWriter, r *http.Request, apiErr *APIError) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(apiErr.Status)
+
+	json.NewEncoder(w).Encode(struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail"`
+		Instance string `json:"instance,omitempty"`
+		Code     string `json:"code"`
+		Details  any    `json:"errors,omitempty"`
+	}{
+		Type:     "about:blank",
+		Title:    http.StatusText(apiErr.Status),
+		Status:   apiErr.Status,
+		Detail:   apiErr.Message,
+		Instance: r.URL.Path,
+		Code:     apiErr.Code,
+		Details:  apiErr.Details,
+	})
+}
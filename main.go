@@ -1,13 +1,21 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 //TIP <p>To run your code, right-click the code and select <b>Run</b>.</p> <p>Alternatively, click
 // the <icon src="AllIcons.Actions.Execute"/> icon in the gutter and select the <b>Run</b> menu item from here.</p>
 
 func main() {
+	if err := loadJWTSecret(); err != nil {
+		log.Fatal(err)
+	}
+
 	store, err := newPostgresStore()
 	if err != nil {
 		log.Fatal(err)
@@ -17,8 +25,43 @@ func main() {
 		log.Fatal(err)
 	}
 
+	tokenStore := newPostgresTokenStore(store.db)
+
+	if err := bootstrapAdmin(store); err != nil {
+		log.Fatal(err)
+	}
+
 	// fmt.Printf("%+v\n", store)
 
-	server := NewApiServer(":3000", store)
+	server := NewApiServer(":3000", store, tokenStore)
 	server.Run()
 }
+
+// bootstrapAdmin creates the initial "admin" user on first startup so
+// there's always at least one account able to log in and grant roles. The
+// password comes from ADMIN_BOOTSTRAP_PASSWORD rather than a well-known
+// default, the same way loadJWTSecret requires JWT_SECRET.
+func bootstrapAdmin(store Storage) error {
+	if _, err := store.GetUserByUsername("admin"); err == nil {
+		return nil
+	}
+
+	password := os.Getenv("ADMIN_BOOTSTRAP_PASSWORD")
+	if password == "" {
+		return fmt.Errorf("ADMIN_BOOTSTRAP_PASSWORD environment variable must be set to bootstrap the initial admin user")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	admin := NewUser("admin", string(hash), []string{"admin"})
+	if err := store.CreateUser(admin); err != nil {
+		return err
+	}
+
+	log.Println("Bootstrapped initial admin user (username: admin) from ADMIN_BOOTSTRAP_PASSWORD")
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+)
+
+func TestBootstrapAdminRequiresPassword(t *testing.T) {
+	os.Unsetenv("ADMIN_BOOTSTRAP_PASSWORD")
+
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	store.EXPECT().GetUserByUsername("admin").Return(nil, ErrUserNotFound)
+
+	if err := bootstrapAdmin(store); err == nil {
+		t.Fatal("expected an error when ADMIN_BOOTSTRAP_PASSWORD is unset, got nil")
+	}
+}
+
+func TestBootstrapAdminCreatesAdminFromEnv(t *testing.T) {
+	t.Setenv("ADMIN_BOOTSTRAP_PASSWORD", "s3cret-enough")
+
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	store.EXPECT().GetUserByUsername("admin").Return(nil, ErrUserNotFound)
+	store.EXPECT().CreateUser(gomock.Any()).DoAndReturn(func(u *User) error {
+		if u.Username != "admin" {
+			t.Errorf("expected username admin, got %q", u.Username)
+		}
+		if u.Password == "s3cret-enough" {
+			t.Error("expected password to be hashed, got the plaintext env value")
+		}
+		return nil
+	})
+
+	if err := bootstrapAdmin(store); err != nil {
+		t.Fatalf("bootstrapAdmin: %v", err)
+	}
+}
+
+func TestBootstrapAdminSkipsExistingAdmin(t *testing.T) {
+	os.Unsetenv("ADMIN_BOOTSTRAP_PASSWORD")
+
+	ctrl := gomock.NewController(t)
+	store := NewMockStorage(ctrl)
+	store.EXPECT().GetUserByUsername("admin").Return(&User{ID: 1, Username: "admin"}, nil)
+
+	if err := bootstrapAdmin(store); err != nil {
+		t.Fatalf("expected no error when admin already exists, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+//go:build integration
+
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// These tests hit a real Postgres instance (see newPostgresStore) and are
+// gated behind the "integration" build tag: go test -tags=integration ./...
+
+func newTestStore(t *testing.T) *PostgresStore {
+	store, err := newPostgresStore()
+	if err != nil {
+		t.Fatalf("newPostgresStore: %v", err)
+	}
+	if err := store.init(); err != nil {
+		t.Fatalf("store.init: %v", err)
+	}
+	return store
+}
+
+func createTestAccount(t *testing.T, store *PostgresStore, userID int, balance float64) *Account {
+	account := NewAccount("Test", "Account", "USD", userID)
+	account.Balance = balance
+	if err := store.CreateAccount(account); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	accounts, _, err := store.GetAccounts(ListAccountsParams{PageID: 1, PageSize: 100})
+	if err != nil {
+		t.Fatalf("GetAccounts: %v", err)
+	}
+	return accounts[len(accounts)-1]
+}
+
+func TestTransferTx(t *testing.T) {
+	store := newTestStore(t)
+
+	from := createTestAccount(t, store, 1, 100)
+	to := createTestAccount(t, store, 1, 0)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        50,
+	})
+	if err != nil {
+		t.Fatalf("TransferTx: %v", err)
+	}
+
+	if result.FromAccount.Balance != from.Balance-50 {
+		t.Errorf("expected from balance %v, got %v", from.Balance-50, result.FromAccount.Balance)
+	}
+	if result.ToAccount.Balance != to.Balance+50 {
+		t.Errorf("expected to balance %v, got %v", to.Balance+50, result.ToAccount.Balance)
+	}
+}
+
+func TestTransferTxInsufficientBalance(t *testing.T) {
+	store := newTestStore(t)
+
+	from := createTestAccount(t, store, 1, 10)
+	to := createTestAccount(t, store, 1, 0)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        50,
+	})
+	if err != ErrInsufficientBalance {
+		t.Fatalf("expected ErrInsufficientBalance, got %v", err)
+	}
+}
+
+func TestTransferTxAccountNotFound(t *testing.T) {
+	store := newTestStore(t)
+
+	from := createTestAccount(t, store, 1, 100)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: from.ID,
+		ToAccountID:   999999,
+		Amount:        50,
+	})
+	if err != ErrAccountNotFound {
+		t.Fatalf("expected ErrAccountNotFound, got %v", err)
+	}
+}
+
+// TestTransferTxConcurrent hammers the same pair of accounts with transfers
+// in both directions to prove the ascending-id lock order in TransferTx
+// never deadlocks.
+func TestTransferTxConcurrent(t *testing.T) {
+	store := newTestStore(t)
+
+	account1 := createTestAccount(t, store, 1, 1000)
+	account2 := createTestAccount(t, store, 1, 1000)
+
+	n := 20
+	amount := 10.0
+	errs := make(chan error, n)
+
+	for i := 0; i < n; i++ {
+		fromID, toID := account1.ID, account2.ID
+		if i%2 == 1 {
+			fromID, toID = account2.ID, account1.ID
+		}
+
+		go func(fromID, toID int) {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: fromID,
+				ToAccountID:   toID,
+				Amount:        amount,
+			})
+			errs <- err
+		}(fromID, toID)
+	}
+
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("TransferTx: %v", err)
+		}
+	}
+}
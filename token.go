@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	sqlc "github.com/IliasPapanikolaou/Golang-API-paradigm/db/sqlc"
+)
+
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 7 * 24 * time.Hour
+)
+
+// RefreshToken is a persisted, rotatable credential. Every login or refresh
+// issues a new row; reusing or revoking one row revokes the whole family.
+type RefreshToken struct {
+	ID        string
+	Family    string
+	UserID    int
+	Used      bool
+	Revoked   bool
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+type TokenStore interface {
+	CreateRefreshToken(*RefreshToken) error
+	GetRefreshToken(id string) (*RefreshToken, error)
+	MarkRefreshTokenUsed(id string) error
+	RevokeFamily(family string) error
+	IsFamilyRevoked(family string) (bool, error)
+	RevokeJTI(jti string) error
+	IsJTIRevoked(jti string) (bool, error)
+}
+
+// PostgresTokenStore shares its underlying connection (and sqlc queries)
+// with PostgresStore; the refresh_tokens table is created by db/migration.
+type PostgresTokenStore struct {
+	*sqlc.Queries
+}
+
+func newPostgresTokenStore(db *sql.DB) *PostgresTokenStore {
+	return &PostgresTokenStore{Queries: sqlc.New(db)}
+}
+
+func (s *PostgresTokenStore) CreateRefreshToken(t *RefreshToken) error {
+	return s.Queries.CreateRefreshToken(context.Background(), sqlc.CreateRefreshTokenParams{
+		ID:        t.ID,
+		Family:    t.Family,
+		UserID:    int64(t.UserID),
+		Used:      t.Used,
+		Revoked:   t.Revoked,
+		ExpiresAt: t.ExpiresAt,
+		CreatedAt: t.CreatedAt,
+	})
+}
+
+func (s *PostgresTokenStore) GetRefreshToken(id string) (*RefreshToken, error) {
+	row, err := s.Queries.GetRefreshToken(context.Background(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RefreshToken{
+		ID:        row.ID,
+		Family:    row.Family,
+		UserID:    int(row.UserID),
+		Used:      row.Used,
+		Revoked:   row.Revoked,
+		ExpiresAt: row.ExpiresAt,
+		CreatedAt: row.CreatedAt,
+	}, nil
+}
+
+func (s *PostgresTokenStore) MarkRefreshTokenUsed(id string) error {
+	return s.Queries.MarkRefreshTokenUsed(context.Background(), id)
+}
+
+func (s *PostgresTokenStore) RevokeFamily(family string) error {
+	return s.Queries.RevokeRefreshTokenFamily(context.Background(), family)
+}
+
+func (s *PostgresTokenStore) IsFamilyRevoked(family string) (bool, error) {
+	return s.Queries.IsRefreshTokenFamilyRevoked(context.Background(), family)
+}
+
+// RevokeJTI denylists a single access token's jti, so it can be rejected by
+// IsJTIRevoked immediately rather than waiting out its remaining TTL.
+func (s *PostgresTokenStore) RevokeJTI(jti string) error {
+	return s.Queries.RevokeJTI(context.Background(), sqlc.RevokeJTIParams{
+		Jti:       jti,
+		RevokedAt: time.Now().UTC(),
+	})
+}
+
+func (s *PostgresTokenStore) IsJTIRevoked(jti string) (bool, error) {
+	return s.Queries.IsJTIRevoked(context.Background(), jti)
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("unable to generate token id: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
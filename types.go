@@ -0,0 +1,129 @@
+package main
+
+import (
+	"math/rand"
+	"time"
+)
+
+type Account struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"userId"`
+	FirstName string    `json:"firstName"`
+	LastName  string    `json:"lastName"`
+	Number    int64     `json:"number"`
+	Balance   float64   `json:"balance"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func NewAccount(firstName, lastName, currency string, userID int) *Account {
+	return &Account{
+		UserID:    userID,
+		FirstName: firstName,
+		LastName:  lastName,
+		Number:    int64(rand.Intn(1000000)),
+		Currency:  currency,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+type CreateAccountRequest struct {
+	FirstName string `json:"firstName" validate:"required,min=1,max=70"`
+	LastName  string `json:"lastName" validate:"required,min=1,max=70"`
+	Currency  string `json:"currency" validate:"omitempty,oneof=USD EUR CAD"`
+}
+
+type TransferRequest struct {
+	FromAccount int     `json:"fromAccount" validate:"required"`
+	ToAccount   int     `json:"toAccount" validate:"required,nefield=FromAccount"`
+	Amount      float64 `json:"amount" validate:"required,gt=0"`
+}
+
+// ListAccountsParams is the page-based pagination the API accepts on
+// GET /account, translated by the store into a LIMIT/OFFSET query.
+type ListAccountsParams struct {
+	PageID   int32 `validate:"required,min=1"`
+	PageSize int32 `validate:"required,min=1,max=100"`
+}
+
+// ListAccountsResult is the paginated response shape for GET /account.
+type ListAccountsResult struct {
+	Data       []*Account `json:"data"`
+	NextPageID *int32     `json:"next_page_id,omitempty"`
+	Total      int64      `json:"total"`
+}
+
+// Entry is a single debit or credit against an account's balance, created
+// in pairs by TransferTx so every balance change is auditable.
+type Entry struct {
+	ID        int64     `json:"id"`
+	AccountID int       `json:"accountId"`
+	Amount    float64   `json:"amount"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Transfer records a completed money movement between two accounts.
+type Transfer struct {
+	ID            int64     `json:"id"`
+	FromAccountID int       `json:"fromAccountId"`
+	ToAccountID   int       `json:"toAccountId"`
+	Amount        float64   `json:"amount"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type TransferTxParams struct {
+	FromAccountID int
+	ToAccountID   int
+	Amount        float64
+}
+
+type TransferTxResult struct {
+	Transfer    *Transfer `json:"transfer"`
+	FromAccount *Account  `json:"fromAccount"`
+	ToAccount   *Account  `json:"toAccount"`
+	FromEntry   *Entry    `json:"fromEntry"`
+	ToEntry     *Entry    `json:"toEntry"`
+}
+
+// User is an application login identity, distinct from Account which
+// represents a bank account. A User can own one or more Accounts.
+type User struct {
+	ID        int       `json:"id"`
+	Username  string    `json:"username"`
+	Password  string    `json:"-"`
+	Roles     []string  `json:"roles"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func NewUser(username, encryptedPassword string, roles []string) *User {
+	return &User{
+		Username:  username,
+		Password:  encryptedPassword,
+		Roles:     roles,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func (u *User) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type LoginResponse struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	User         *User  `json:"user"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}